@@ -0,0 +1,96 @@
+// Command backfill-hashes is a one-shot migration that computes
+// content_sha256 for every images row that predates the deduplication
+// column, by downloading each row's stored_url in a bounded worker pool.
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+
+	"github.com/joho/godotenv"
+	"imgvault/internal/database"
+)
+
+// workerCount bounds how many stored_url downloads run at once, so a large
+// backlog of images doesn't open an unbounded number of outbound requests.
+const workerCount = 8
+
+type target struct {
+	id        string
+	storedURL string
+}
+
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using environment variables")
+	}
+
+	db, err := database.InitDB()
+	if err != nil {
+		log.Fatal("Failed to initialize database:", err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query(`SELECT id, stored_url FROM images WHERE content_sha256 IS NULL`)
+	if err != nil {
+		log.Fatal("Failed to query images:", err)
+	}
+
+	var targets []target
+	for rows.Next() {
+		var t target
+		if err := rows.Scan(&t.id, &t.storedURL); err != nil {
+			log.Printf("Error scanning row: %v", err)
+			continue
+		}
+		targets = append(targets, t)
+	}
+	rows.Close()
+
+	log.Printf("Backfilling content_sha256 for %d images using %d workers...", len(targets), workerCount)
+
+	jobs := make(chan target)
+	var wg sync.WaitGroup
+	for i := 0; i < workerCount; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for t := range jobs {
+				hash, err := hashStoredURL(t.storedURL)
+				if err != nil {
+					log.Printf("Failed to hash image %s: %v", t.id, err)
+					continue
+				}
+				if _, err := db.Exec(`UPDATE images SET content_sha256 = $1 WHERE id = $2`, hash, t.id); err != nil {
+					log.Printf("Failed to update image %s: %v", t.id, err)
+				}
+			}
+		}()
+	}
+
+	for _, t := range targets {
+		jobs <- t
+	}
+	close(jobs)
+	wg.Wait()
+
+	log.Println("Backfill complete.")
+}
+
+func hashStoredURL(url string) (string, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, resp.Body); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
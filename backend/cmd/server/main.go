@@ -7,6 +7,7 @@ import (
 	"github.com/joho/godotenv"
 	"imgvault/internal/api"
 	"imgvault/internal/database"
+	"imgvault/internal/services/storage"
 )
 
 func main() {
@@ -27,6 +28,14 @@ func main() {
 		log.Fatal("Failed to run migrations:", err)
 	}
 
+	// Register storage backends from configuration
+	if err := storage.LoadFromEnv(); err != nil {
+		log.Fatal("Failed to configure storage backends:", err)
+	}
+	if len(storage.Names()) == 0 {
+		log.Println("Warning: no storage backends configured")
+	}
+
 	// Get server port
 	port := os.Getenv("PORT")
 	if port == "" {
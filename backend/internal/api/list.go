@@ -0,0 +1,236 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"imgvault/internal/models"
+)
+
+const (
+	defaultListLimit = 50
+	maxListLimit     = 200
+)
+
+// listCursor is the decoded form of the opaque ?cursor= value: the
+// (created_at, id) of the last row of the previous page, used for keyset
+// pagination so results stay stable even as new images are inserted.
+type listCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+func encodeCursor(c listCursor) string {
+	raw := fmt.Sprintf("%s|%s", c.CreatedAt.Format(time.RFC3339Nano), c.ID)
+	return base64.URLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(s string) (*listCursor, error) {
+	raw, err := base64.URLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	parts := strings.SplitN(string(raw), "|", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	return &listCursor{CreatedAt: createdAt, ID: parts[1]}, nil
+}
+
+// handleGetImages returns a filtered, cursor-paginated page of images.
+// Supported query params: tags (comma-separated, AND semantics), q
+// (ILIKE search across page_title/notes/source_page_url), file_type,
+// backend, created_before, created_after (RFC3339), limit, and cursor
+// (opaque, from the previous page's next_cursor).
+func (s *Server) handleGetImages(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	limit := defaultListLimit
+	if raw := q.Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			respondWithError(w, http.StatusBadRequest, "Invalid limit")
+			return
+		}
+		limit = n
+	}
+	if limit > maxListLimit {
+		limit = maxListLimit
+	}
+
+	var filterArgs []interface{}
+	var filterConds []string
+	addFilter := func(cond string, arg interface{}) {
+		filterArgs = append(filterArgs, arg)
+		filterConds = append(filterConds, fmt.Sprintf(cond, len(filterArgs)))
+	}
+
+	if tagsStr := q.Get("tags"); tagsStr != "" {
+		tags := strings.Split(tagsStr, ",")
+		for i := range tags {
+			tags[i] = strings.TrimSpace(tags[i])
+		}
+		addFilter("tags @> $%d", pq.Array(tags))
+	}
+	if search := q.Get("q"); search != "" {
+		filterArgs = append(filterArgs, "%"+search+"%")
+		idx := len(filterArgs)
+		filterConds = append(filterConds, fmt.Sprintf(
+			"(page_title ILIKE $%d OR notes ILIKE $%d OR source_page_url ILIKE $%d)", idx, idx, idx,
+		))
+	}
+	if fileType := q.Get("file_type"); fileType != "" {
+		addFilter("file_type = $%d", fileType)
+	}
+	if backend := q.Get("backend"); backend != "" {
+		addFilter("backend = $%d", backend)
+	}
+	if before := q.Get("created_before"); before != "" {
+		t, err := time.Parse(time.RFC3339, before)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid created_before")
+			return
+		}
+		addFilter("created_at < $%d", t)
+	}
+	if after := q.Get("created_after"); after != "" {
+		t, err := time.Parse(time.RFC3339, after)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid created_after")
+			return
+		}
+		addFilter("created_at > $%d", t)
+	}
+
+	var cursor *listCursor
+	if cursorStr := q.Get("cursor"); cursorStr != "" {
+		var err error
+		cursor, err = decodeCursor(cursorStr)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid cursor")
+			return
+		}
+	}
+
+	where := ""
+	if len(filterConds) > 0 {
+		where = "WHERE " + strings.Join(filterConds, " AND ")
+	}
+
+	var totalEstimate int64
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM images %s", where)
+	if err := s.db.QueryRow(countQuery, filterArgs...).Scan(&totalEstimate); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to count images")
+		return
+	}
+
+	pageArgs := append([]interface{}(nil), filterArgs...)
+	pageConds := append([]string(nil), filterConds...)
+	if cursor != nil {
+		pageArgs = append(pageArgs, cursor.CreatedAt, cursor.ID)
+		pageConds = append(pageConds, fmt.Sprintf("(created_at, id) < ($%d, $%d)", len(pageArgs)-1, len(pageArgs)))
+	}
+
+	pageWhere := ""
+	if len(pageConds) > 0 {
+		pageWhere = "WHERE " + strings.Join(pageConds, " AND ")
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, stored_url, source_image_url, source_page_url, page_title,
+		       file_type, file_size, notes, tags, backend, content_sha256, created_at
+		FROM images
+		%s
+		ORDER BY created_at DESC, id DESC
+		LIMIT %d
+	`, pageWhere, limit+1)
+
+	rows, err := s.db.Query(query, pageArgs...)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to fetch images")
+		return
+	}
+	defer rows.Close()
+
+	images := []models.Image{}
+	for rows.Next() {
+		var img models.Image
+		var tags pq.StringArray
+		var contentHash sql.NullString
+		err := rows.Scan(
+			&img.ID, &img.StoredURL, &img.SourceImageURL, &img.SourcePageURL,
+			&img.PageTitle, &img.FileType, &img.FileSize, &img.Notes, &tags, &img.Backend, &contentHash, &img.CreatedAt,
+		)
+		if err != nil {
+			log.Printf("Error scanning row: %v", err)
+			continue
+		}
+		img.Tags = tags
+		img.ContentSHA256 = contentHash.String
+		images = append(images, img)
+	}
+
+	var nextCursor string
+	if len(images) > limit {
+		last := images[limit-1]
+		nextCursor = encodeCursor(listCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+		images = images[:limit]
+	}
+
+	ids := make([]string, len(images))
+	for i, img := range images {
+		ids[i] = img.ID
+	}
+	variantsByImage, err := s.getImageVariantsBatch(ids)
+	if err != nil {
+		log.Printf("Error fetching variants for page: %v", err)
+	}
+	for i := range images {
+		images[i].Variants = variantsByImage[images[i].ID]
+	}
+
+	respondWithJSON(w, http.StatusOK, models.ImageListResponse{
+		Items:         images,
+		NextCursor:    nextCursor,
+		TotalEstimate: totalEstimate,
+	})
+}
+
+// handleGetImageTags returns per-tag usage counts across all images, for
+// rendering a tag cloud.
+func (s *Server) handleGetImageTags(w http.ResponseWriter, r *http.Request) {
+	rows, err := s.db.Query(`
+		SELECT unnest(tags) AS tag, COUNT(*)
+		FROM images
+		GROUP BY 1
+		ORDER BY 2 DESC
+	`)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to fetch tags")
+		return
+	}
+	defer rows.Close()
+
+	tags := []models.TagCount{}
+	for rows.Next() {
+		var t models.TagCount
+		if err := rows.Scan(&t.Tag, &t.Count); err != nil {
+			log.Printf("Error scanning tag row: %v", err)
+			continue
+		}
+		tags = append(tags, t)
+	}
+
+	respondWithJSON(w, http.StatusOK, tags)
+}
@@ -0,0 +1,276 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/lib/pq"
+	"imgvault/internal/models"
+	"imgvault/internal/services/storage"
+)
+
+// handleMirrorUpload fans an upload out to every backend configured via
+// MIRROR_BACKENDS, persisting one images row (backend="mirror") plus one
+// image_mirrors row per backend so replication state can be inspected and
+// repaired later.
+func (s *Server) handleMirrorUpload(
+	w http.ResponseWriter,
+	r *http.Request,
+	file io.Reader,
+	original []byte,
+	header *multipart.FileHeader,
+	fileType, contentHash string,
+	sourceImageURL, sourcePageURL, pageTitle, notes string,
+	tags []string,
+) {
+	mirror, err := storage.MirrorFromEnv()
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	log.Printf("Mirroring file %s to %d backends (quorum %d)...", header.Filename, len(mirror.Backends), mirror.Quorum)
+
+	var (
+		mu      sync.Mutex
+		results []storage.MirrorResult
+	)
+	stragglers, err := mirror.Upload(r.Context(), file, header.Filename, fileType, func(res storage.MirrorResult) {
+		mu.Lock()
+		results = append(results, res)
+		mu.Unlock()
+	})
+	if err != nil {
+		log.Printf("Mirror upload error: %v", err)
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	var primary storage.MirrorResult
+	mu.Lock()
+	for _, res := range results {
+		if res.Err == nil {
+			primary = res
+			break
+		}
+	}
+	snapshot := append([]storage.MirrorResult(nil), results...)
+	mu.Unlock()
+
+	var imageID string
+	var alreadyVaulted bool
+	query := `
+		INSERT INTO images (stored_url, source_image_url, source_page_url, page_title, file_type, file_size, notes, tags, backend, content_sha256)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id
+	`
+	err = s.db.QueryRow(
+		query,
+		primary.Stored.URL,
+		sourceImageURL,
+		sourcePageURL,
+		pageTitle,
+		fileType,
+		header.Size,
+		notes,
+		pq.Array(tags),
+		"mirror",
+		contentHash,
+	).Scan(&imageID)
+	if err != nil {
+		existing, ferr := s.existingImageOnDuplicateHash(err, contentHash)
+		if ferr != nil {
+			log.Printf("Database error: %v", err)
+			respondWithError(w, http.StatusInternalServerError, "Failed to save to database")
+			return
+		}
+		// A concurrent upload of the same bytes won the race to create the
+		// images row. Still record this request's mirror results against
+		// that row instead of discarding them, so the backends we just
+		// uploaded to aren't left with no image_mirrors row pointing at them.
+		imageID = existing.ID
+		alreadyVaulted = true
+	}
+
+	for _, res := range snapshot {
+		if err := s.saveMirrorResult(imageID, res); err != nil {
+			log.Printf("Error saving mirror result for %s: %v", res.Backend, err)
+		}
+	}
+
+	// Backends that hadn't reported in by the time quorum was reached keep
+	// uploading in the background; persist their outcome as it arrives so
+	// GET .../mirrors and the retry endpoint see a complete picture.
+	if stragglers != nil {
+		go func(imageID string) {
+			for res := range stragglers {
+				if err := s.saveMirrorResult(imageID, res); err != nil {
+					log.Printf("Error saving late mirror result for %s: %v", res.Backend, err)
+				}
+			}
+		}(imageID)
+	}
+
+	if !alreadyVaulted {
+		if primaryBackend, err := storage.Get(primary.Backend); err == nil {
+			s.generateVariants(r.Context(), primaryBackend, imageID, original)
+		}
+	}
+
+	image, err := s.getImageByID(imageID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve saved image")
+		return
+	}
+
+	message, status := "Image mirrored successfully", http.StatusCreated
+	if alreadyVaulted {
+		message, status = "Image already vaulted; recorded additional mirror copies", http.StatusOK
+	}
+
+	respondWithJSON(w, status, models.UploadResponse{
+		Success: true,
+		Message: message,
+		Image:   image,
+	})
+}
+
+func (s *Server) saveMirrorResult(imageID string, res storage.MirrorResult) error {
+	status := "success"
+	errMsg := ""
+	if res.Err != nil {
+		status = "failed"
+		errMsg = res.Err.Error()
+	}
+
+	_, err := s.db.Exec(
+		`INSERT INTO image_mirrors (image_id, backend, stored_url, status, error) VALUES ($1, $2, $3, $4, $5)`,
+		imageID, res.Backend, res.Stored.URL, status, errMsg,
+	)
+	return err
+}
+
+// handleGetImageMirrors returns the per-backend replication state for an image.
+func (s *Server) handleGetImageMirrors(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	rows, err := s.db.Query(
+		`SELECT id, image_id, backend, stored_url, status, error, created_at
+		 FROM image_mirrors WHERE image_id = $1 ORDER BY created_at`,
+		id,
+	)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to fetch mirrors")
+		return
+	}
+	defer rows.Close()
+
+	mirrors := []models.ImageMirror{}
+	for rows.Next() {
+		var m models.ImageMirror
+		var storedURL, errMsg sql.NullString
+		if err := rows.Scan(&m.ID, &m.ImageID, &m.Backend, &storedURL, &m.Status, &errMsg, &m.CreatedAt); err != nil {
+			log.Printf("Error scanning mirror row: %v", err)
+			continue
+		}
+		m.StoredURL = storedURL.String
+		m.Error = errMsg.String
+		mirrors = append(mirrors, m)
+	}
+
+	respondWithJSON(w, http.StatusOK, mirrors)
+}
+
+// handleRetryImageMirrors re-uploads any non-successful mirror rows for an
+// image, sourcing the bytes from a backend that already has a good copy.
+func (s *Server) handleRetryImageMirrors(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	rows, err := s.db.Query(
+		`SELECT id, backend, stored_url, status FROM image_mirrors WHERE image_id = $1`,
+		id,
+	)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to fetch mirrors")
+		return
+	}
+
+	type mirrorRow struct {
+		id, backend, storedURL, status string
+	}
+	var all []mirrorRow
+	for rows.Next() {
+		var m mirrorRow
+		var storedURL sql.NullString
+		if err := rows.Scan(&m.id, &m.backend, &storedURL, &m.status); err != nil {
+			log.Printf("Error scanning mirror row: %v", err)
+			continue
+		}
+		m.storedURL = storedURL.String
+		all = append(all, m)
+	}
+	rows.Close()
+
+	var source string
+	for _, m := range all {
+		if m.status == "success" {
+			source = m.storedURL
+			break
+		}
+	}
+	if source == "" {
+		respondWithError(w, http.StatusConflict, "No successful mirror to retry from")
+		return
+	}
+
+	retried := 0
+	for _, m := range all {
+		if m.status == "success" {
+			continue
+		}
+
+		backend, err := storage.Get(m.backend)
+		if err != nil {
+			s.updateMirrorStatus(m.id, "failed", err.Error(), "")
+			continue
+		}
+
+		resp, err := http.Get(source)
+		if err != nil {
+			s.updateMirrorStatus(m.id, "failed", err.Error(), "")
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			s.updateMirrorStatus(m.id, "failed", fmt.Sprintf("source fetch returned status %d", resp.StatusCode), "")
+			continue
+		}
+		stored, err := backend.Upload(r.Context(), resp.Body, "retry", resp.Header.Get("Content-Type"))
+		resp.Body.Close()
+		if err != nil {
+			s.updateMirrorStatus(m.id, "failed", err.Error(), "")
+			continue
+		}
+
+		s.updateMirrorStatus(m.id, "success", "", stored.URL)
+		retried++
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"retried": retried,
+	})
+}
+
+func (s *Server) updateMirrorStatus(mirrorID, status, errMsg, storedURL string) {
+	query := `UPDATE image_mirrors SET status = $1, error = $2, stored_url = COALESCE(NULLIF($3, ''), stored_url) WHERE id = $4`
+	if _, err := s.db.Exec(query, status, errMsg, storedURL, mirrorID); err != nil {
+		log.Printf("Error updating mirror status: %v", err)
+	}
+}
@@ -1,11 +1,16 @@
 package api
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"mime/multipart"
 	"net/http"
 	"strings"
 
@@ -13,7 +18,7 @@ import (
 	"github.com/lib/pq"
 	"github.com/rs/cors"
 	"imgvault/internal/models"
-	"imgvault/internal/services/pixvid"
+	"imgvault/internal/services/storage"
 )
 
 type Server struct {
@@ -53,8 +58,12 @@ func (s *Server) setupRoutes() {
 
 	api.HandleFunc("/upload", s.handleUpload).Methods("POST", "OPTIONS")
 	api.HandleFunc("/images", s.handleGetImages).Methods("GET")
+	api.HandleFunc("/images/tags", s.handleGetImageTags).Methods("GET")
+	api.HandleFunc("/images/by-hash/{sha256}", s.handleGetImageByHash).Methods("GET", "HEAD")
 	api.HandleFunc("/images/{id}", s.handleGetImage).Methods("GET")
 	api.HandleFunc("/images/{id}", s.handleDeleteImage).Methods("DELETE")
+	api.HandleFunc("/images/{id}/mirrors", s.handleGetImageMirrors).Methods("GET")
+	api.HandleFunc("/images/{id}/mirrors/retry", s.handleRetryImageMirrors).Methods("POST")
 	api.HandleFunc("/health", s.handleHealth).Methods("GET")
 }
 
@@ -90,50 +99,63 @@ func (s *Server) handleUpload(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// Upload to Pixvid
-	log.Printf("Uploading file %s to Pixvid...", header.Filename)
-	storedURL, err := pixvid.UploadToPixvid(file, header.Filename)
-	if err != nil {
-		log.Printf("Pixvid upload error: %v", err)
-		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to upload to Pixvid: %v", err))
-		return
-	}
-
 	// Determine file type
 	fileType := header.Header.Get("Content-Type")
 	if fileType == "" {
 		fileType = "application/octet-stream"
 	}
 
-	// Save to database
-	var imageID string
-	query := `
-		INSERT INTO images (stored_url, source_image_url, source_page_url, page_title, file_type, file_size, notes, tags)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-		RETURNING id
-	`
-	err = s.db.QueryRow(
-		query,
-		storedURL,
-		sourceImageURL,
-		sourcePageURL,
-		pageTitle,
-		fileType,
-		header.Size,
-		notes,
-		pq.Array(tags),
-	).Scan(&imageID)
+	// Hash the upload while buffering it, so we can short-circuit on content
+	// we've already vaulted instead of re-uploading it to a remote backend.
+	var buf bytes.Buffer
+	hasher := sha256.New()
+	if _, err := io.Copy(&buf, io.TeeReader(file, hasher)); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to read uploaded file")
+		return
+	}
+	contentHash := hex.EncodeToString(hasher.Sum(nil))
+
+	if existing, err := s.getImageByHash(contentHash); err == nil {
+		respondWithJSON(w, http.StatusOK, models.UploadResponse{
+			Success: true,
+			Message: "Image already vaulted",
+			Image:   existing,
+		})
+		return
+	} else if err != sql.ErrNoRows {
+		respondWithError(w, http.StatusInternalServerError, "Failed to check for duplicate image")
+		return
+	}
+
+	original := buf.Bytes()
+	dedupedFile := bytes.NewReader(original)
 
+	// ?mirror=true fans the upload out to every backend in MIRROR_BACKENDS
+	// instead of the usual single-backend flow.
+	if r.URL.Query().Get("mirror") == "true" {
+		s.handleMirrorUpload(w, r, dedupedFile, original, header, fileType, contentHash, sourceImageURL, sourcePageURL, pageTitle, notes, tags)
+		return
+	}
+
+	// Pick the storage backend: explicit query param wins, then the
+	// X-Storage-Backend header, then the configured default.
+	backend, err := s.resolveBackend(r)
 	if err != nil {
-		log.Printf("Database error: %v", err)
-		respondWithError(w, http.StatusInternalServerError, "Failed to save to database")
+		respondWithError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	// Fetch the created image
-	image, err := s.getImageByID(imageID)
+	// Accept: application/x-ndjson gets a stream of progress frames instead
+	// of a single JSON response.
+	if wantsNDJSON(r) {
+		s.handleStreamingUpload(w, r, backend, dedupedFile, original, header, fileType, contentHash, sourceImageURL, sourcePageURL, pageTitle, notes, tags)
+		return
+	}
+
+	image, err := s.uploadSingle(r, backend, dedupedFile, original, header, fileType, contentHash, sourceImageURL, sourcePageURL, pageTitle, notes, tags)
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve saved image")
+		log.Printf("Upload error: %v", err)
+		respondWithError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -144,39 +166,68 @@ func (s *Server) handleUpload(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// handleGetImages returns all images
-func (s *Server) handleGetImages(w http.ResponseWriter, r *http.Request) {
+// uploadSingle uploads file via backend and persists the resulting image
+// row, shared by both the plain and ndjson-streaming upload paths. original
+// is the full, already-buffered upload, used to generate derivatives.
+func (s *Server) uploadSingle(
+	r *http.Request,
+	backend storage.Backend,
+	file io.Reader,
+	original []byte,
+	header *multipart.FileHeader,
+	fileType, contentHash, sourceImageURL, sourcePageURL, pageTitle, notes string,
+	tags []string,
+) (*models.Image, error) {
+	log.Printf("Uploading file %s via %s backend...", header.Filename, backend.Name())
+	stored, err := backend.Upload(r.Context(), file, header.Filename, fileType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload via %s: %w", backend.Name(), err)
+	}
+
+	var imageID string
 	query := `
-		SELECT id, stored_url, source_image_url, source_page_url, page_title, 
-		       file_type, file_size, notes, tags, created_at
-		FROM images
-		ORDER BY created_at DESC
+		INSERT INTO images (stored_url, source_image_url, source_page_url, page_title, file_type, file_size, notes, tags, backend, content_sha256)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id
 	`
-
-	rows, err := s.db.Query(query)
+	err = s.db.QueryRow(
+		query,
+		stored.URL,
+		sourceImageURL,
+		sourcePageURL,
+		pageTitle,
+		fileType,
+		header.Size,
+		notes,
+		pq.Array(tags),
+		backend.Name(),
+		contentHash,
+	).Scan(&imageID)
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Failed to fetch images")
-		return
-	}
-	defer rows.Close()
-
-	images := []models.Image{}
-	for rows.Next() {
-		var img models.Image
-		var tags pq.StringArray
-		err := rows.Scan(
-			&img.ID, &img.StoredURL, &img.SourceImageURL, &img.SourcePageURL,
-			&img.PageTitle, &img.FileType, &img.FileSize, &img.Notes, &tags, &img.CreatedAt,
-		)
-		if err != nil {
-			log.Printf("Error scanning row: %v", err)
-			continue
+		if existing, ferr := s.existingImageOnDuplicateHash(err, contentHash); ferr == nil {
+			// A concurrent upload of the same bytes won the race to create
+			// the images row, so this request's own upload is now an
+			// orphan: no row references it. Clean it up rather than leak
+			// it on the backend.
+			if backend.Capabilities().SupportsDelete {
+				if derr := backend.Delete(r.Context(), stored.Name); derr != nil {
+					log.Printf("Error deleting orphaned upload %s from %s: %v", stored.Name, backend.Name(), derr)
+				}
+			} else {
+				log.Printf("Orphaned duplicate upload %s on %s has no Delete support; leaving it in place", stored.Name, backend.Name())
+			}
+			return existing, nil
 		}
-		img.Tags = tags
-		images = append(images, img)
+		return nil, fmt.Errorf("failed to save to database: %w", err)
 	}
 
-	respondWithJSON(w, http.StatusOK, images)
+	s.generateVariants(r.Context(), backend, imageID, original)
+
+	image, err := s.getImageByID(imageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve saved image: %w", err)
+	}
+	return image, nil
 }
 
 // handleGetImage returns a single image by ID
@@ -221,6 +272,30 @@ func (s *Server) handleDeleteImage(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// handleGetImageByHash returns the image with the given content hash, so the
+// browser extension can check whether an image is already vaulted before
+// attempting a multipart upload.
+func (s *Server) handleGetImageByHash(w http.ResponseWriter, r *http.Request) {
+	hash := mux.Vars(r)["sha256"]
+
+	image, err := s.getImageByHash(hash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Failed to fetch image")
+		return
+	}
+
+	if r.Method == http.MethodHead {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, image)
+}
+
 // handleHealth returns server health status
 func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 	respondWithJSON(w, http.StatusOK, map[string]interface{}{
@@ -231,24 +306,92 @@ func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 
 // Helper functions
 
+// resolveBackend picks the storage backend for an upload request: an
+// explicit ?backend= query param wins, then the X-Storage-Backend header,
+// then the configured default.
+func (s *Server) resolveBackend(r *http.Request) (storage.Backend, error) {
+	name := r.URL.Query().Get("backend")
+	if name == "" {
+		name = r.Header.Get("X-Storage-Backend")
+	}
+	if name == "" {
+		return storage.Default()
+	}
+	return storage.Get(name)
+}
+
 func (s *Server) getImageByID(id string) (*models.Image, error) {
 	query := `
 		SELECT id, stored_url, source_image_url, source_page_url, page_title,
-		       file_type, file_size, notes, tags, created_at
+		       file_type, file_size, notes, tags, backend, content_sha256, created_at
 		FROM images
 		WHERE id = $1
 	`
+	img, err := scanImage(s.db.QueryRow(query, id))
+	if err != nil {
+		return nil, err
+	}
+	if img.Variants, err = s.getImageVariants(img.ID); err != nil {
+		return nil, err
+	}
+	return img, nil
+}
+
+// getImageByHash looks up an image by its content_sha256, used both to
+// short-circuit re-uploads of content we already have and to serve
+// GET/HEAD /api/images/by-hash/{sha256}.
+func (s *Server) getImageByHash(hash string) (*models.Image, error) {
+	query := `
+		SELECT id, stored_url, source_image_url, source_page_url, page_title,
+		       file_type, file_size, notes, tags, backend, content_sha256, created_at
+		FROM images
+		WHERE content_sha256 = $1
+	`
+	img, err := scanImage(s.db.QueryRow(query, hash))
+	if err != nil {
+		return nil, err
+	}
+	if img.Variants, err = s.getImageVariants(img.ID); err != nil {
+		return nil, err
+	}
+	return img, nil
+}
+
+// isDuplicateContentHash reports whether err is a violation of the unique
+// index on content_sha256, i.e. a concurrent upload of the same bytes won
+// the race to insert its row first. The pre-check in handleUpload catches
+// the common case, but only this index makes it airtight.
+func isDuplicateContentHash(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == "23505" && pqErr.Constraint == "idx_images_content_sha256_unique"
+	}
+	return false
+}
+
+// existingImageOnDuplicateHash returns the row a concurrent upload of the
+// same bytes already created when insertErr is a content_sha256 unique
+// violation, or insertErr itself (wrapped as-is) otherwise.
+func (s *Server) existingImageOnDuplicateHash(insertErr error, contentHash string) (*models.Image, error) {
+	if !isDuplicateContentHash(insertErr) {
+		return nil, insertErr
+	}
+	return s.getImageByHash(contentHash)
+}
 
+func scanImage(row *sql.Row) (*models.Image, error) {
 	var img models.Image
 	var tags pq.StringArray
-	err := s.db.QueryRow(query, id).Scan(
+	var contentHash sql.NullString
+	err := row.Scan(
 		&img.ID, &img.StoredURL, &img.SourceImageURL, &img.SourcePageURL,
-		&img.PageTitle, &img.FileType, &img.FileSize, &img.Notes, &tags, &img.CreatedAt,
+		&img.PageTitle, &img.FileType, &img.FileSize, &img.Notes, &tags, &img.Backend, &contentHash, &img.CreatedAt,
 	)
 	if err != nil {
 		return nil, err
 	}
 	img.Tags = tags
+	img.ContentSHA256 = contentHash.String
 	return &img, nil
 }
 
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"strings"
+
+	"imgvault/internal/models"
+	"imgvault/internal/services/storage"
+	"imgvault/internal/streamformatter"
+)
+
+// wantsNDJSON reports whether the client asked for a newline-delimited JSON
+// progress stream instead of a single JSON response.
+func wantsNDJSON(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "application/x-ndjson")
+}
+
+// handleStreamingUpload performs the same upload as uploadSingle, but emits
+// ndjson progress frames as the backend reads the already-buffered file,
+// finishing with a {"status":"done","aux":...} or {"errorDetail":{...}} frame.
+func (s *Server) handleStreamingUpload(
+	w http.ResponseWriter,
+	r *http.Request,
+	backend storage.Backend,
+	file io.Reader,
+	original []byte,
+	header *multipart.FileHeader,
+	fileType, contentHash, sourceImageURL, sourcePageURL, pageTitle, notes string,
+	tags []string,
+) {
+	streamer := streamformatter.NewStreamer(w)
+	if streamer == nil {
+		respondWithError(w, http.StatusInternalServerError, "Streaming not supported by this connection")
+		return
+	}
+
+	counting := streamformatter.NewCountingReader(file, func(current int64) {
+		streamer.Status("Uploading", header.Filename, &streamformatter.Progress{Current: current, Total: header.Size})
+	})
+
+	image, err := s.uploadSingle(r, backend, counting, original, header, fileType, contentHash, sourceImageURL, sourcePageURL, pageTitle, notes, tags)
+	if err != nil {
+		log.Printf("Streaming upload error: %v", err)
+		streamer.Error(err)
+		return
+	}
+
+	streamer.Done(models.UploadResponse{
+		Success: true,
+		Message: "Image uploaded successfully",
+		Image:   image,
+	})
+}
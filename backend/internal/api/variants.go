@@ -0,0 +1,96 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/lib/pq"
+	"imgvault/internal/models"
+	"imgvault/internal/services/imaging"
+	"imgvault/internal/services/storage"
+)
+
+// generateVariants decodes original, produces the configured derivatives,
+// and persists each as an image_variants row uploaded through backend. If
+// decoding or encoding fails, no variants are stored; a derivative that
+// fails to upload or save is logged and skipped. Neither fails the upload.
+func (s *Server) generateVariants(ctx context.Context, backend storage.Backend, imageID string, original []byte) {
+	derivatives, err := imaging.Generate(original, imaging.DefaultSpecs)
+	if err != nil {
+		log.Printf("Error generating variants for image %s: %v", imageID, err)
+		return
+	}
+
+	for _, d := range derivatives {
+		filename := fmt.Sprintf("%s-%s", imageID, d.Purpose)
+		stored, err := backend.Upload(ctx, bytes.NewReader(d.Data), filename, d.ContentType)
+		if err != nil {
+			log.Printf("Error uploading %s variant for image %s: %v", d.Purpose, imageID, err)
+			continue
+		}
+
+		_, err = s.db.Exec(
+			`INSERT INTO image_variants (image_id, purpose, width, height, content_type, stored_url)
+			 VALUES ($1, $2, $3, $4, $5, $6)`,
+			imageID, d.Purpose, d.Width, d.Height, d.ContentType, stored.URL,
+		)
+		if err != nil {
+			log.Printf("Error saving %s variant for image %s: %v", d.Purpose, imageID, err)
+		}
+	}
+}
+
+// getImageVariants returns the derivatives generated for an image, ordered
+// smallest-first.
+func (s *Server) getImageVariants(imageID string) ([]models.ImageVariant, error) {
+	rows, err := s.db.Query(
+		`SELECT id, image_id, purpose, width, height, content_type, stored_url
+		 FROM image_variants WHERE image_id = $1 ORDER BY width`,
+		imageID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	variants := []models.ImageVariant{}
+	for rows.Next() {
+		var v models.ImageVariant
+		if err := rows.Scan(&v.ID, &v.ImageID, &v.Purpose, &v.Width, &v.Height, &v.ContentType, &v.StoredURL); err != nil {
+			return nil, err
+		}
+		variants = append(variants, v)
+	}
+	return variants, nil
+}
+
+// getImageVariantsBatch returns the derivatives for every id in imageIDs in
+// a single round trip, keyed by image ID, for callers hydrating a page of
+// images without issuing one query per row.
+func (s *Server) getImageVariantsBatch(imageIDs []string) (map[string][]models.ImageVariant, error) {
+	byImage := make(map[string][]models.ImageVariant, len(imageIDs))
+	if len(imageIDs) == 0 {
+		return byImage, nil
+	}
+
+	rows, err := s.db.Query(
+		`SELECT id, image_id, purpose, width, height, content_type, stored_url
+		 FROM image_variants WHERE image_id = ANY($1) ORDER BY image_id, width`,
+		pq.Array(imageIDs),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var v models.ImageVariant
+		if err := rows.Scan(&v.ID, &v.ImageID, &v.Purpose, &v.Width, &v.Height, &v.ContentType, &v.StoredURL); err != nil {
+			return nil, err
+		}
+		byImage[v.ImageID] = append(byImage[v.ImageID], v)
+	}
+	return byImage, nil
+}
@@ -62,8 +62,53 @@ func RunMigrations(db *sql.DB) error {
 		created_at TIMESTAMPTZ DEFAULT NOW()
 	);
 
+	ALTER TABLE images ADD COLUMN IF NOT EXISTS backend TEXT NOT NULL DEFAULT 'pixvid';
+	ALTER TABLE images ADD COLUMN IF NOT EXISTS content_sha256 TEXT;
+
 	CREATE INDEX IF NOT EXISTS idx_images_created_at ON images(created_at DESC);
 	CREATE INDEX IF NOT EXISTS idx_images_tags ON images USING GIN(tags);
+	CREATE INDEX IF NOT EXISTS idx_images_backend ON images(backend);
+	-- Rows with a duplicate hash can already exist from before app-level
+	-- dedup landed (or from backfill-hashes hashing two legacy rows to the
+	-- same value); null out all but the oldest of each group so the unique
+	-- index below can be created. A no-op once the table is clean.
+	UPDATE images SET content_sha256 = NULL WHERE id IN (
+		SELECT id FROM (
+			SELECT id, ROW_NUMBER() OVER (PARTITION BY content_sha256 ORDER BY created_at) AS rn
+			FROM images WHERE content_sha256 IS NOT NULL
+		) ranked WHERE rn > 1
+	);
+
+	-- Partial (NULLs excluded) so pre-migration rows without a hash don't
+	-- collide, but two uploads of the same bytes can never both win the
+	-- dedup race: the loser's INSERT fails with a unique violation instead
+	-- of silently creating a duplicate row.
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_images_content_sha256_unique ON images(content_sha256) WHERE content_sha256 IS NOT NULL;
+
+	CREATE TABLE IF NOT EXISTS image_mirrors (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		image_id UUID NOT NULL REFERENCES images(id) ON DELETE CASCADE,
+		backend TEXT NOT NULL,
+		stored_url TEXT,
+		status TEXT NOT NULL DEFAULT 'pending',
+		error TEXT,
+		created_at TIMESTAMPTZ DEFAULT NOW()
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_image_mirrors_image_id ON image_mirrors(image_id);
+
+	CREATE TABLE IF NOT EXISTS image_variants (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		image_id UUID NOT NULL REFERENCES images(id) ON DELETE CASCADE,
+		purpose TEXT NOT NULL,
+		width INTEGER,
+		height INTEGER,
+		content_type TEXT,
+		stored_url TEXT NOT NULL,
+		created_at TIMESTAMPTZ DEFAULT NOW()
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_image_variants_image_id ON image_variants(image_id);
 	`
 
 	_, err := db.Exec(query)
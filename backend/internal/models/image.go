@@ -4,16 +4,43 @@ import "time"
 
 // Image represents an image record in the database
 type Image struct {
-	ID              string    `json:"id"`
-	StoredURL       string    `json:"stored_url"`
-	SourceImageURL  string    `json:"source_image_url,omitempty"`
-	SourcePageURL   string    `json:"source_page_url,omitempty"`
-	PageTitle       string    `json:"page_title,omitempty"`
-	FileType        string    `json:"file_type"`
-	FileSize        int64     `json:"file_size"`
-	Notes           string    `json:"notes,omitempty"`
-	Tags            []string  `json:"tags,omitempty"`
-	CreatedAt       time.Time `json:"created_at"`
+	ID             string         `json:"id"`
+	StoredURL      string         `json:"stored_url"`
+	SourceImageURL string         `json:"source_image_url,omitempty"`
+	SourcePageURL  string         `json:"source_page_url,omitempty"`
+	PageTitle      string         `json:"page_title,omitempty"`
+	FileType       string         `json:"file_type"`
+	FileSize       int64          `json:"file_size"`
+	Notes          string         `json:"notes,omitempty"`
+	Tags           []string       `json:"tags,omitempty"`
+	Backend        string         `json:"backend"`
+	ContentSHA256  string         `json:"content_sha256,omitempty"`
+	Variants       []ImageVariant `json:"variants,omitempty"`
+	CreatedAt      time.Time      `json:"created_at"`
+}
+
+// ImageVariant is a resized derivative of an image (e.g. "thumb", "medium",
+// "display"), stored through the same storage.Backend as the original.
+type ImageVariant struct {
+	ID          string `json:"id"`
+	ImageID     string `json:"image_id"`
+	Purpose     string `json:"purpose"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+	ContentType string `json:"content_type,omitempty"`
+	StoredURL   string `json:"stored_url"`
+}
+
+// ImageMirror represents one backend's copy of an image uploaded via the
+// mirrored-upload flow.
+type ImageMirror struct {
+	ID        string    `json:"id"`
+	ImageID   string    `json:"image_id"`
+	Backend   string    `json:"backend"`
+	StoredURL string    `json:"stored_url,omitempty"`
+	Status    string    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 // UploadRequest represents the request body for uploading an image
@@ -31,3 +58,17 @@ type UploadResponse struct {
 	Message string `json:"message"`
 	Image   *Image `json:"image,omitempty"`
 }
+
+// ImageListResponse is the paginated result of a filtered image listing.
+type ImageListResponse struct {
+	Items         []Image `json:"items"`
+	NextCursor    string  `json:"next_cursor,omitempty"`
+	TotalEstimate int64   `json:"total_estimate"`
+}
+
+// TagCount is one entry of the /api/images/tags aggregation, used to render
+// a tag cloud.
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int64  `json:"count"`
+}
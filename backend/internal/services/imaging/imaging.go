@@ -0,0 +1,108 @@
+// Package imaging generates resized derivatives (thumb/medium/display) of an
+// uploaded image, so the extension can render a responsive gallery without
+// hotlinking full-resolution originals.
+package imaging
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// Derivative is one resized copy of a source image.
+type Derivative struct {
+	Purpose     string
+	Width       int
+	Height      int
+	ContentType string
+	Data        []byte
+}
+
+// Spec describes one derivative to generate: a purpose name and the target
+// length of its longest edge, in pixels.
+type Spec struct {
+	Purpose string
+	MaxSize int
+}
+
+// DefaultSpecs are the derivatives generated for every upload.
+var DefaultSpecs = []Spec{
+	{Purpose: "thumb", MaxSize: 240},
+	{Purpose: "medium", MaxSize: 800},
+	{Purpose: "display", MaxSize: 1600},
+}
+
+// Generate decodes src (JPEG, PNG, GIF, or WebP) and produces one Derivative
+// per spec using a high-quality Catmull-Rom resampler, skipping any spec
+// that would upscale the original.
+func Generate(src []byte, specs []Spec) ([]Derivative, error) {
+	img, format, err := image.Decode(bytes.NewReader(src))
+	if err != nil {
+		return nil, fmt.Errorf("imaging: error decoding image: %w", err)
+	}
+
+	bounds := img.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+
+	var derivatives []Derivative
+	for _, spec := range specs {
+		if spec.MaxSize >= srcW && spec.MaxSize >= srcH {
+			continue
+		}
+
+		w, h := fitDimensions(srcW, srcH, spec.MaxSize)
+		dst := image.NewRGBA(image.Rect(0, 0, w, h))
+		draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Over, nil)
+
+		var buf bytes.Buffer
+		contentType, err := encode(&buf, dst, format)
+		if err != nil {
+			return nil, fmt.Errorf("imaging: error encoding %s derivative: %w", spec.Purpose, err)
+		}
+
+		derivatives = append(derivatives, Derivative{
+			Purpose:     spec.Purpose,
+			Width:       w,
+			Height:      h,
+			ContentType: contentType,
+			Data:        buf.Bytes(),
+		})
+	}
+
+	return derivatives, nil
+}
+
+func fitDimensions(srcW, srcH, maxSize int) (int, int) {
+	if srcW >= srcH {
+		return maxSize, max(1, srcH*maxSize/srcW)
+	}
+	return max(1, srcW*maxSize/srcH), maxSize
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// encode writes dst in the same family of format as the original (GIF and
+// PNG keep their format to preserve transparency; everything else, WebP
+// included, is re-encoded as JPEG since Go's standard library has no WebP
+// encoder).
+func encode(buf *bytes.Buffer, dst image.Image, format string) (string, error) {
+	switch format {
+	case "png":
+		return "image/png", png.Encode(buf, dst)
+	case "gif":
+		return "image/gif", gif.Encode(buf, dst, nil)
+	default:
+		return "image/jpeg", jpeg.Encode(buf, dst, &jpeg.Options{Quality: 85})
+	}
+}
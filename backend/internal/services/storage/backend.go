@@ -0,0 +1,36 @@
+// Package storage defines a provider-agnostic interface for persisting
+// uploaded images, so the API layer does not need to know whether a given
+// image ends up on Pixvid, ImgBB, an S3-compatible bucket, or local disk.
+package storage
+
+import (
+	"context"
+	"io"
+)
+
+// StoredObject describes where an upload ended up once a Backend has
+// finished accepting it.
+type StoredObject struct {
+	URL  string
+	Name string
+	Size int64
+}
+
+// Caps describes what a Backend supports, so callers can make decisions
+// (e.g. whether to offer deletion) without type-asserting on concrete types.
+type Caps struct {
+	SupportsDelete bool
+	MaxFileSize    int64
+}
+
+// Backend is implemented by every storage provider. Implementations must be
+// safe for concurrent use.
+type Backend interface {
+	// Name is the backend's registry key, e.g. "pixvid" or "s3".
+	Name() string
+	// Upload streams file to the backend and returns where it was stored.
+	Upload(ctx context.Context, file io.Reader, filename, contentType string) (StoredObject, error)
+	// Delete removes a previously uploaded object by its backend-specific id.
+	Delete(ctx context.Context, id string) error
+	Capabilities() Caps
+}
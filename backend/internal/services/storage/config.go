@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// providerConfig is one entry of the optional YAML config file pointed to by
+// STORAGE_CONFIG_FILE. Individual provider env vars (e.g. PIXVID_API_KEY)
+// always take precedence when both are set.
+type providerConfig struct {
+	Name     string `yaml:"name"`
+	APIKey   string `yaml:"api_key"`
+	Bucket   string `yaml:"bucket"`
+	Region   string `yaml:"region"`
+	Endpoint string `yaml:"endpoint"`
+	Dir      string `yaml:"dir"`
+}
+
+type fileConfig struct {
+	DefaultBackend string           `yaml:"default_backend"`
+	Providers      []providerConfig `yaml:"providers"`
+}
+
+// LoadFromEnv registers whichever backends have enough configuration present
+// in the environment (and, if STORAGE_CONFIG_FILE is set, a YAML file), and
+// is intended to be called once from main at startup.
+func LoadFromEnv() error {
+	if path := os.Getenv("STORAGE_CONFIG_FILE"); path != "" {
+		if err := loadFromFile(path); err != nil {
+			return err
+		}
+	}
+
+	if apiKey := os.Getenv("PIXVID_API_KEY"); apiKey != "" {
+		Register(NewPixvidBackend(apiKey))
+	}
+	if apiKey := os.Getenv("IMGBB_API_KEY"); apiKey != "" {
+		Register(NewImgBBBackend(apiKey))
+	}
+	if bucket := os.Getenv("S3_BUCKET"); bucket != "" {
+		Register(NewS3Backend(S3Config{
+			Bucket:   bucket,
+			Region:   os.Getenv("S3_REGION"),
+			Endpoint: os.Getenv("S3_ENDPOINT"),
+		}))
+	}
+	if dir := os.Getenv("LOCAL_STORAGE_DIR"); dir != "" {
+		b, err := NewLocalBackend(dir, os.Getenv("LOCAL_STORAGE_BASE_URL"))
+		if err != nil {
+			return fmt.Errorf("storage: local backend: %w", err)
+		}
+		Register(b)
+	}
+
+	return nil
+}
+
+func loadFromFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("storage: reading config file: %w", err)
+	}
+
+	var cfg fileConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return fmt.Errorf("storage: parsing config file: %w", err)
+	}
+
+	for _, p := range cfg.Providers {
+		switch p.Name {
+		case "pixvid":
+			if p.APIKey != "" {
+				Register(NewPixvidBackend(p.APIKey))
+			}
+		case "imgbb":
+			if p.APIKey != "" {
+				Register(NewImgBBBackend(p.APIKey))
+			}
+		case "s3":
+			Register(NewS3Backend(S3Config{Bucket: p.Bucket, Region: p.Region, Endpoint: p.Endpoint}))
+		case "local":
+			b, err := NewLocalBackend(p.Dir, p.Endpoint)
+			if err != nil {
+				return fmt.Errorf("storage: local backend: %w", err)
+			}
+			Register(b)
+		default:
+			return fmt.Errorf("storage: unknown provider %q in config file", p.Name)
+		}
+	}
+
+	if cfg.DefaultBackend != "" {
+		SetDefault(cfg.DefaultBackend)
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+)
+
+// imgbbResponse represents the response from ImgBB's upload API.
+type imgbbResponse struct {
+	Success bool `json:"success"`
+	Data    struct {
+		ID    string `json:"id"`
+		Image struct {
+			Filename string `json:"filename"`
+			Size     int64  `json:"size,string"`
+		} `json:"image"`
+		URL string `json:"url"`
+	} `json:"data"`
+}
+
+// imgbbBackend stores images on imgbb.com.
+type imgbbBackend struct {
+	apiKey string
+	client *http.Client
+}
+
+// NewImgBBBackend returns a Backend that uploads to imgbb.com.
+func NewImgBBBackend(apiKey string) Backend {
+	return &imgbbBackend{apiKey: apiKey, client: &http.Client{}}
+}
+
+func (b *imgbbBackend) Name() string { return "imgbb" }
+
+func (b *imgbbBackend) Capabilities() Caps {
+	return Caps{SupportsDelete: false, MaxFileSize: 32 << 20}
+}
+
+func (b *imgbbBackend) Upload(ctx context.Context, file io.Reader, filename, contentType string) (StoredObject, error) {
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return StoredObject{}, fmt.Errorf("error reading file: %w", err)
+	}
+
+	var requestBody bytes.Buffer
+	writer := multipart.NewWriter(&requestBody)
+	if err := writer.WriteField("image", base64.StdEncoding.EncodeToString(data)); err != nil {
+		return StoredObject{}, fmt.Errorf("error writing form field: %w", err)
+	}
+	if err := writer.WriteField("name", filename); err != nil {
+		return StoredObject{}, fmt.Errorf("error writing form field: %w", err)
+	}
+	writer.Close()
+
+	apiURL := "https://api.imgbb.com/1/upload?" + url.Values{"key": {b.apiKey}}.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, &requestBody)
+	if err != nil {
+		return StoredObject{}, fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return StoredObject{}, fmt.Errorf("error sending request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return StoredObject{}, fmt.Errorf("error reading response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return StoredObject{}, fmt.Errorf("imgbb API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var result imgbbResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return StoredObject{}, fmt.Errorf("error parsing response: %w", err)
+	}
+
+	if !result.Success || result.Data.URL == "" {
+		return StoredObject{}, fmt.Errorf("imgbb upload failed: %s", string(body))
+	}
+
+	return StoredObject{URL: result.Data.URL, Name: result.Data.Image.Filename, Size: result.Data.Image.Size}, nil
+}
+
+func (b *imgbbBackend) Delete(ctx context.Context, id string) error {
+	return fmt.Errorf("imgbb: delete is not supported by this backend")
+}
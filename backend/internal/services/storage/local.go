@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// localBackend stores images on local disk, for self-hosted setups that
+// don't want a dependency on any third-party image host.
+type localBackend struct {
+	dir     string
+	baseURL string
+}
+
+// NewLocalBackend returns a Backend that writes uploads under dir, serving
+// them back at baseURL+"/"+name, where name is a random UUID prefixed to the
+// upload's base filename. dir is created if it doesn't exist.
+func NewLocalBackend(dir, baseURL string) (Backend, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("error creating local storage dir: %w", err)
+	}
+	return &localBackend{dir: dir, baseURL: strings.TrimRight(baseURL, "/")}, nil
+}
+
+func (b *localBackend) Name() string { return "local" }
+
+func (b *localBackend) Capabilities() Caps {
+	return Caps{SupportsDelete: true}
+}
+
+func (b *localBackend) Upload(ctx context.Context, file io.Reader, filename, contentType string) (StoredObject, error) {
+	name := fmt.Sprintf("%s-%s", uuid.NewString(), filepath.Base(filename))
+	dest := filepath.Join(b.dir, name)
+
+	out, err := os.Create(dest)
+	if err != nil {
+		return StoredObject{}, fmt.Errorf("error creating local file: %w", err)
+	}
+	defer out.Close()
+
+	size, err := io.Copy(out, file)
+	if err != nil {
+		return StoredObject{}, fmt.Errorf("error writing local file: %w", err)
+	}
+
+	return StoredObject{URL: b.baseURL + "/" + name, Name: name, Size: size}, nil
+}
+
+func (b *localBackend) Delete(ctx context.Context, id string) error {
+	if err := os.Remove(filepath.Join(b.dir, filepath.Base(id))); err != nil {
+		return fmt.Errorf("error deleting local file: %w", err)
+	}
+	return nil
+}
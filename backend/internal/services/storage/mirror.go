@@ -0,0 +1,126 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// MirrorResult is the outcome of uploading to a single backend as part of a
+// mirrored upload.
+type MirrorResult struct {
+	Backend string
+	Stored  StoredObject
+	Err     error
+}
+
+// MirrorUploader fans an upload out to multiple backends concurrently and
+// succeeds once a configurable quorum of them has acknowledged, similar to
+// the parallel push-to-multiple-registries pattern used elsewhere.
+type MirrorUploader struct {
+	Backends []Backend
+	Quorum   int
+}
+
+// NewMirrorUploader builds a MirrorUploader over backends, requiring quorum
+// of them to succeed. quorum is clamped to [1, len(backends)].
+func NewMirrorUploader(backends []Backend, quorum int) *MirrorUploader {
+	if quorum < 1 {
+		quorum = 1
+	}
+	if quorum > len(backends) {
+		quorum = len(backends)
+	}
+	return &MirrorUploader{Backends: backends, Quorum: quorum}
+}
+
+// Upload buffers file once, then uploads the buffered copy to every backend
+// concurrently, returning as soon as Quorum backends have acknowledged.
+// onResult is called synchronously for every outcome, success or failure,
+// received before Upload returns.
+//
+// Any backends still in flight at that point ("stragglers") are not waited
+// on, but their eventual outcome must still be persisted: the returned
+// channel yields one MirrorResult per straggler and is closed once all of
+// them have reported in. Callers that care about complete replication state
+// (they should) must drain it — typically in a background goroutine started
+// once whatever the result needs to be associated with (e.g. a DB row) has
+// been created. A nil channel means every backend had already reported in
+// by the time quorum was reached.
+func (m *MirrorUploader) Upload(ctx context.Context, file io.Reader, filename, contentType string, onResult func(MirrorResult)) (<-chan MirrorResult, error) {
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("mirror: error buffering upload: %w", err)
+	}
+
+	results := make(chan MirrorResult, len(m.Backends))
+	for _, b := range m.Backends {
+		go func(b Backend) {
+			stored, err := b.Upload(ctx, bytes.NewReader(data), filename, contentType)
+			results <- MirrorResult{Backend: b.Name(), Stored: stored, Err: err}
+		}(b)
+	}
+
+	var success, total int
+	for total < len(m.Backends) {
+		r := <-results
+		total++
+		if r.Err == nil {
+			success++
+		}
+		onResult(r)
+		if success >= m.Quorum {
+			break
+		}
+	}
+
+	var stragglers chan MirrorResult
+	if remaining := len(m.Backends) - total; remaining > 0 {
+		stragglers = make(chan MirrorResult, remaining)
+		go func() {
+			defer close(stragglers)
+			for i := 0; i < remaining; i++ {
+				stragglers <- <-results
+			}
+		}()
+	}
+
+	if success < m.Quorum {
+		return stragglers, fmt.Errorf("mirror: only %d/%d backends acknowledged, quorum is %d", success, len(m.Backends), m.Quorum)
+	}
+	return stragglers, nil
+}
+
+// MirrorFromEnv builds a MirrorUploader from the MIRROR_BACKENDS
+// (comma-separated registry names) and MIRROR_QUORUM environment variables.
+func MirrorFromEnv() (*MirrorUploader, error) {
+	namesStr := os.Getenv("MIRROR_BACKENDS")
+	if namesStr == "" {
+		return nil, fmt.Errorf("storage: MIRROR_BACKENDS is not configured")
+	}
+
+	var backends []Backend
+	for _, name := range strings.Split(namesStr, ",") {
+		name = strings.TrimSpace(name)
+		b, err := Get(name)
+		if err != nil {
+			return nil, err
+		}
+		backends = append(backends, b)
+	}
+
+	quorum := len(backends)
+	if q := os.Getenv("MIRROR_QUORUM"); q != "" {
+		n, err := strconv.Atoi(q)
+		if err != nil {
+			return nil, fmt.Errorf("storage: invalid MIRROR_QUORUM: %w", err)
+		}
+		quorum = n
+	}
+
+	return NewMirrorUploader(backends, quorum), nil
+}
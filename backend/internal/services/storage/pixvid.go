@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+)
+
+// pixvidResponse represents the response from Pixvid's API (Chevereto).
+type pixvidResponse struct {
+	StatusCode int `json:"status_code"`
+	Image      struct {
+		Name string `json:"name"`
+		Size int64  `json:"size"`
+		URL  string `json:"url"`
+	} `json:"image"`
+}
+
+// pixvidBackend stores images on pixvid.org.
+type pixvidBackend struct {
+	apiKey string
+	client *http.Client
+}
+
+// NewPixvidBackend returns a Backend that uploads to pixvid.org.
+func NewPixvidBackend(apiKey string) Backend {
+	return &pixvidBackend{apiKey: apiKey, client: &http.Client{}}
+}
+
+func (b *pixvidBackend) Name() string { return "pixvid" }
+
+func (b *pixvidBackend) Capabilities() Caps {
+	return Caps{SupportsDelete: false}
+}
+
+func (b *pixvidBackend) Upload(ctx context.Context, file io.Reader, filename, contentType string) (StoredObject, error) {
+	var requestBody bytes.Buffer
+	writer := multipart.NewWriter(&requestBody)
+
+	part, err := writer.CreateFormFile("source", filename)
+	if err != nil {
+		return StoredObject{}, fmt.Errorf("error creating form file: %w", err)
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return StoredObject{}, fmt.Errorf("error copying file: %w", err)
+	}
+	writer.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://pixvid.org/api/1/upload", &requestBody)
+	if err != nil {
+		return StoredObject{}, fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("X-API-Key", b.apiKey)
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return StoredObject{}, fmt.Errorf("error sending request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return StoredObject{}, fmt.Errorf("error reading response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return StoredObject{}, fmt.Errorf("pixvid API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var result pixvidResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return StoredObject{}, fmt.Errorf("error parsing response: %w", err)
+	}
+
+	if result.StatusCode != 200 || result.Image.URL == "" {
+		return StoredObject{}, fmt.Errorf("pixvid upload failed with status code: %d", result.StatusCode)
+	}
+
+	return StoredObject{URL: result.Image.URL, Name: result.Image.Name, Size: result.Image.Size}, nil
+}
+
+func (b *pixvidBackend) Delete(ctx context.Context, id string) error {
+	return fmt.Errorf("pixvid: delete is not supported by this backend")
+}
@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+var (
+	mu          sync.RWMutex
+	backends    = map[string]Backend{}
+	defaultName string
+)
+
+// Register adds a backend to the registry under its own Name().
+func Register(b Backend) {
+	mu.Lock()
+	defer mu.Unlock()
+	backends[b.Name()] = b
+}
+
+// Get returns a registered backend by name.
+func Get(name string) (Backend, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+	b, ok := backends[name]
+	if !ok {
+		return nil, fmt.Errorf("storage: unknown backend %q", name)
+	}
+	return b, nil
+}
+
+// Default returns the backend to use when the caller doesn't request one by
+// name: a name set via SetDefault (e.g. the config file's default_backend)
+// comes first, then STORAGE_DEFAULT_BACKEND, falling back to "pixvid" for
+// backwards compatibility with installs that predate this package.
+func Default() (Backend, error) {
+	mu.RLock()
+	name := defaultName
+	mu.RUnlock()
+	if name == "" {
+		name = os.Getenv("STORAGE_DEFAULT_BACKEND")
+	}
+	if name == "" {
+		name = "pixvid"
+	}
+	return Get(name)
+}
+
+// SetDefault overrides the default backend name.
+func SetDefault(name string) {
+	mu.Lock()
+	defaultName = name
+	mu.Unlock()
+}
+
+// Names returns the registry keys of all currently registered backends.
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	names := make([]string, 0, len(backends))
+	for n := range backends {
+		names = append(names, n)
+	}
+	return names
+}
@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/google/uuid"
+)
+
+// S3Config configures an S3-compatible object store backend. Endpoint may be
+// left empty to talk to AWS S3 directly, or set to point at a compatible
+// provider such as R2, Backblaze B2, or a self-hosted MinIO.
+type S3Config struct {
+	Bucket   string
+	Region   string
+	Endpoint string
+}
+
+// s3Backend stores images in an S3-compatible bucket.
+type s3Backend struct {
+	cfg      S3Config
+	client   *s3.Client
+	uploader *manager.Uploader
+}
+
+// NewS3Backend returns a Backend backed by an S3-compatible object store.
+func NewS3Backend(cfg S3Config) Backend {
+	awsCfg, err := config.LoadDefaultConfig(context.Background(), config.WithRegion(cfg.Region))
+	if err != nil {
+		// Deferred to first use: Upload/Delete will surface a clear error
+		// rather than panicking during registry construction.
+		return &s3Backend{cfg: cfg}
+	}
+
+	client := s3.NewFromConfig(awsCfg, func(o *s3.Options) {
+		if cfg.Endpoint != "" {
+			o.BaseEndpoint = aws.String(cfg.Endpoint)
+			o.UsePathStyle = true
+		}
+	})
+
+	return &s3Backend{
+		cfg:      cfg,
+		client:   client,
+		uploader: manager.NewUploader(client),
+	}
+}
+
+func (b *s3Backend) Name() string { return "s3" }
+
+func (b *s3Backend) Capabilities() Caps {
+	return Caps{SupportsDelete: true}
+}
+
+func (b *s3Backend) Upload(ctx context.Context, file io.Reader, filename, contentType string) (StoredObject, error) {
+	if b.uploader == nil {
+		return StoredObject{}, fmt.Errorf("s3: backend not configured: %w", errNotConfigured)
+	}
+
+	key := fmt.Sprintf("%s-%s", uuid.NewString(), sanitizeKey(filename))
+
+	out, err := b.uploader.Upload(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(b.cfg.Bucket),
+		Key:         aws.String(key),
+		Body:        file,
+		ContentType: aws.String(contentType),
+	})
+	if err != nil {
+		return StoredObject{}, fmt.Errorf("s3: error uploading object: %w", err)
+	}
+
+	return StoredObject{URL: out.Location, Name: key}, nil
+}
+
+func (b *s3Backend) Delete(ctx context.Context, id string) error {
+	if b.client == nil {
+		return fmt.Errorf("s3: backend not configured: %w", errNotConfigured)
+	}
+
+	_, err := b.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(b.cfg.Bucket),
+		Key:    aws.String(id),
+	})
+	if err != nil {
+		return fmt.Errorf("s3: error deleting object: %w", err)
+	}
+	return nil
+}
+
+var errNotConfigured = fmt.Errorf("missing AWS credentials or region")
+
+func sanitizeKey(filename string) string {
+	return strings.ReplaceAll(url.QueryEscape(filename), "%", "_")
+}
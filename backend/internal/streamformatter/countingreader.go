@@ -0,0 +1,27 @@
+package streamformatter
+
+import "io"
+
+// CountingReader wraps an io.Reader, calling onRead after every successful
+// Read with the cumulative number of bytes read so far.
+type CountingReader struct {
+	r      io.Reader
+	total  int64
+	onRead func(total int64)
+}
+
+// NewCountingReader wraps r, invoking onRead as bytes are consumed.
+func NewCountingReader(r io.Reader, onRead func(total int64)) *CountingReader {
+	return &CountingReader{r: r, onRead: onRead}
+}
+
+func (c *CountingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	if n > 0 {
+		c.total += int64(n)
+		if c.onRead != nil {
+			c.onRead(c.total)
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,76 @@
+// Package streamformatter writes newline-delimited JSON progress events for
+// long-running operations (uploads, mirror replication, migrations), so a
+// client that asks for Accept: application/x-ndjson can render a real
+// progress bar instead of a spinner.
+package streamformatter
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Progress reports how far a long-running operation has gotten.
+type Progress struct {
+	Current int64 `json:"current"`
+	Total   int64 `json:"total,omitempty"`
+}
+
+// ErrorDetail carries a failure message in the final frame of a stream.
+type ErrorDetail struct {
+	Message string `json:"message"`
+}
+
+// Message is one frame of the ndjson stream.
+type Message struct {
+	Status      string       `json:"status,omitempty"`
+	Progress    *Progress    `json:"progress,omitempty"`
+	ID          string       `json:"id,omitempty"`
+	Aux         interface{}  `json:"aux,omitempty"`
+	ErrorDetail *ErrorDetail `json:"errorDetail,omitempty"`
+}
+
+// Streamer writes Messages as newline-delimited JSON, flushing after every
+// frame so a client sees each one as it's produced.
+type Streamer struct {
+	flusher http.Flusher
+	enc     *json.Encoder
+}
+
+// NewStreamer prepares w for ndjson streaming, setting the appropriate
+// content type and sending the response headers immediately. It returns nil
+// if w doesn't support flushing, so the caller can fall back to a normal
+// JSON response.
+func NewStreamer(w http.ResponseWriter) *Streamer {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return nil
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	return &Streamer{flusher: flusher, enc: json.NewEncoder(w)}
+}
+
+// Status writes a progress frame.
+func (s *Streamer) Status(status, id string, progress *Progress) {
+	s.write(Message{Status: status, ID: id, Progress: progress})
+}
+
+// Done writes the final success frame, carrying the operation's result in aux.
+func (s *Streamer) Done(aux interface{}) {
+	s.write(Message{Status: "done", Aux: aux})
+}
+
+// Error writes the final error frame.
+func (s *Streamer) Error(err error) {
+	s.write(Message{ErrorDetail: &ErrorDetail{Message: err.Error()}})
+}
+
+func (s *Streamer) write(msg Message) {
+	if err := s.enc.Encode(msg); err != nil {
+		return
+	}
+	s.flusher.Flush()
+}